@@ -0,0 +1,130 @@
+/* cache.go
+
+FeatureCache memoizes a Featurizer's output for a given image file, keyed
+by absolute path + file mtime + featurizer name, so re-running the
+pipeline while sweeping parameters (hashFactor, patch size, k, ...)
+doesn't require re-decoding every JPEG whose features haven't changed.
+Wraps an in-memory LRU with an optional on-disk tier that persists
+entries across runs.
+
+@Author: Joyce Noah-Vanhoucke
+*/
+
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/gob"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	lru "github.com/hashicorp/golang-lru/v2"
+)
+
+// defaultFeatureCacheSize is the in-memory LRU size used when a
+// subcommand doesn't expose its own --cache-size flag.
+const defaultFeatureCacheSize = 10000
+
+// FeatureCache memoizes Featurizer output by file path + mtime +
+// featurizer name. An in-memory LRU serves repeated lookups within a
+// single run; when CacheDir is set, entries are also persisted to disk
+// as small gob blobs so later runs can skip decoding entirely.
+type FeatureCache struct {
+	mem      *lru.Cache[string, []float64]
+	cacheDir string
+}
+
+// NewFeatureCache builds a FeatureCache with an in-memory LRU of memSize
+// entries. If cacheDir is non-empty, entries are additionally persisted
+// there across runs.
+func NewFeatureCache(memSize int, cacheDir string) (*FeatureCache, error) {
+	mem, err := lru.New[string, []float64](memSize)
+	if err != nil {
+		return nil, err
+	}
+	if cacheDir != "" {
+		if err := os.MkdirAll(cacheDir, 0755); err != nil {
+			return nil, err
+		}
+	}
+	return &FeatureCache{mem: mem, cacheDir: cacheDir}, nil
+}
+
+// Get returns the cached feature vector for path under featurizerName,
+// provided the file's mtime matches what was cached.
+func (c *FeatureCache) Get(path string, mtime time.Time, featurizerName string) ([]float64, bool) {
+	key, err := cacheKey(path, mtime, featurizerName)
+	if err != nil {
+		return nil, false
+	}
+
+	if features, ok := c.mem.Get(key); ok {
+		return features, true
+	}
+
+	if c.cacheDir == "" {
+		return nil, false
+	}
+	features, err := readCacheEntry(c.diskPath(key))
+	if err != nil {
+		return nil, false
+	}
+	c.mem.Add(key, features)
+	return features, true
+}
+
+// Put records features for path under featurizerName, in memory and, if
+// configured, on disk.
+func (c *FeatureCache) Put(path string, mtime time.Time, featurizerName string, features []float64) {
+	key, err := cacheKey(path, mtime, featurizerName)
+	if err != nil {
+		return
+	}
+	c.mem.Add(key, features)
+
+	if c.cacheDir != "" {
+		if err := writeCacheEntry(c.diskPath(key), features); err != nil {
+			fmt.Fprintf(os.Stderr, "FeatureCache.Put(): error writing disk cache entry: %v\n", err)
+		}
+	}
+}
+
+func (c *FeatureCache) diskPath(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(c.cacheDir, hex.EncodeToString(sum[:])+".gob")
+}
+
+func cacheKey(path string, mtime time.Time, featurizerName string) (string, error) {
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%s|%d|%s", absPath, mtime.UnixNano(), featurizerName), nil
+}
+
+func readCacheEntry(path string) ([]float64, error) {
+	fi, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer fi.Close()
+
+	var features []float64
+	if err := gob.NewDecoder(fi).Decode(&features); err != nil {
+		return nil, err
+	}
+	return features, nil
+}
+
+func writeCacheEntry(path string, features []float64) error {
+	fo, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer fo.Close()
+
+	return gob.NewEncoder(fo).Encode(features)
+}
@@ -0,0 +1,124 @@
+package main
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestFeatureCacheGetMissWhenEmpty(t *testing.T) {
+	cache, err := NewFeatureCache(10, "")
+	if err != nil {
+		t.Fatalf("NewFeatureCache() error = %v", err)
+	}
+
+	if _, ok := cache.Get("galaxy.jpg", time.Unix(0, 0), "central-pixel"); ok {
+		t.Error("Get() on an empty cache reported a hit")
+	}
+}
+
+func TestFeatureCachePutGetInMemory(t *testing.T) {
+	cache, err := NewFeatureCache(10, "")
+	if err != nil {
+		t.Fatalf("NewFeatureCache() error = %v", err)
+	}
+
+	mtime := time.Unix(1700000000, 0)
+	want := []float64{1, 2, 3}
+	cache.Put("galaxy.jpg", mtime, "central-pixel", want)
+
+	got, ok := cache.Get("galaxy.jpg", mtime, "central-pixel")
+	if !ok {
+		t.Fatal("Get() after Put() reported a miss")
+	}
+	if len(got) != len(want) {
+		t.Fatalf("Get() = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("Get() = %v, want %v", got, want)
+		}
+	}
+}
+
+func TestFeatureCacheMissOnMtimeChange(t *testing.T) {
+	cache, err := NewFeatureCache(10, "")
+	if err != nil {
+		t.Fatalf("NewFeatureCache() error = %v", err)
+	}
+
+	cache.Put("galaxy.jpg", time.Unix(1700000000, 0), "central-pixel", []float64{1, 2, 3})
+
+	if _, ok := cache.Get("galaxy.jpg", time.Unix(1700000001, 0), "central-pixel"); ok {
+		t.Error("Get() with a different mtime reported a hit")
+	}
+}
+
+func TestFeatureCacheMissOnFeaturizerNameChange(t *testing.T) {
+	cache, err := NewFeatureCache(10, "")
+	if err != nil {
+		t.Fatalf("NewFeatureCache() error = %v", err)
+	}
+
+	mtime := time.Unix(1700000000, 0)
+	cache.Put("galaxy.jpg", mtime, "central-pixel", []float64{1, 2, 3})
+
+	if _, ok := cache.Get("galaxy.jpg", mtime, "thumbnail"); ok {
+		t.Error("Get() with a different featurizer name reported a hit")
+	}
+}
+
+func TestFeatureCachePutGetOnDisk(t *testing.T) {
+	cacheDir := filepath.Join(t.TempDir(), "feature-cache")
+	cache, err := NewFeatureCache(10, cacheDir)
+	if err != nil {
+		t.Fatalf("NewFeatureCache() error = %v", err)
+	}
+
+	mtime := time.Unix(1700000000, 0)
+	want := []float64{4, 5, 6}
+	cache.Put("galaxy.jpg", mtime, "central-pixel", want)
+
+	// A fresh cache pointed at the same directory has nothing in its
+	// in-memory LRU, so a hit here only comes from the on-disk tier.
+	diskOnly, err := NewFeatureCache(10, cacheDir)
+	if err != nil {
+		t.Fatalf("NewFeatureCache() error = %v", err)
+	}
+	got, ok := diskOnly.Get("galaxy.jpg", mtime, "central-pixel")
+	if !ok {
+		t.Fatal("Get() against the on-disk tier reported a miss")
+	}
+	if len(got) != len(want) {
+		t.Fatalf("Get() = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("Get() = %v, want %v", got, want)
+		}
+	}
+}
+
+func TestCacheKeyDiffersByAbsolutePathMtimeAndFeaturizer(t *testing.T) {
+	mtime := time.Unix(1700000000, 0)
+	base, err := cacheKey("galaxy.jpg", mtime, "central-pixel")
+	if err != nil {
+		t.Fatalf("cacheKey() error = %v", err)
+	}
+
+	other, err := cacheKey("galaxy.jpg", mtime, "thumbnail")
+	if err != nil {
+		t.Fatalf("cacheKey() error = %v", err)
+	}
+	if base == other {
+		t.Error("cacheKey() did not change with featurizerName")
+	}
+
+	sameAgain, err := cacheKey("galaxy.jpg", mtime, "central-pixel")
+	if err != nil {
+		t.Fatalf("cacheKey() error = %v", err)
+	}
+	if base != sameAgain {
+		t.Error("cacheKey() is not deterministic for identical inputs")
+	}
+}
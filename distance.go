@@ -0,0 +1,62 @@
+/* distance.go
+
+Distance metrics used by the k-NN classifier to compare feature vectors.
+
+@Author: Joyce Noah-Vanhoucke
+*/
+
+package main
+
+import "math"
+
+// Distance measures the dissimilarity between two feature vectors of
+// equal length. Smaller values mean the vectors are more alike.
+type Distance interface {
+	Compute(a, b []float64) float64
+}
+
+// EuclideanDistance is the straight-line distance between two vectors.
+type EuclideanDistance struct{}
+
+func (EuclideanDistance) Compute(a, b []float64) float64 {
+	sum := 0.0
+	for i := range a {
+		d := a[i] - b[i]
+		sum += d * d
+	}
+	return math.Sqrt(sum)
+}
+
+// ManhattanDistance is the sum of absolute differences between vectors.
+type ManhattanDistance struct{}
+
+func (ManhattanDistance) Compute(a, b []float64) float64 {
+	sum := 0.0
+	for i := range a {
+		sum += math.Abs(a[i] - b[i])
+	}
+	return sum
+}
+
+// CosineDistance is 1 minus the cosine similarity between vectors, so
+// that, like the other Distance implementations, smaller means closer.
+// Vectors with zero magnitude are treated as maximally distant from
+// everything but themselves -- two zero-magnitude vectors are identical
+// and score a perfect match.
+type CosineDistance struct{}
+
+func (CosineDistance) Compute(a, b []float64) float64 {
+	dot, normA, normB := 0.0, 0.0, 0.0
+	for i := range a {
+		dot += a[i] * b[i]
+		normA += a[i] * a[i]
+		normB += b[i] * b[i]
+	}
+	if normA == 0 && normB == 0 {
+		return 0.0
+	}
+	if normA == 0 || normB == 0 {
+		return 1.0
+	}
+	return 1.0 - dot/(math.Sqrt(normA)*math.Sqrt(normB))
+}
@@ -0,0 +1,34 @@
+package main
+
+import (
+	"math"
+	"testing"
+)
+
+func TestDistances(t *testing.T) {
+	cases := []struct {
+		name     string
+		distance Distance
+		a, b     []float64
+		want     float64
+	}{
+		{"euclidean", EuclideanDistance{}, []float64{0, 0}, []float64{3, 4}, 5},
+		{"euclidean identical", EuclideanDistance{}, []float64{1, 2, 3}, []float64{1, 2, 3}, 0},
+		{"manhattan", ManhattanDistance{}, []float64{0, 0}, []float64{3, 4}, 7},
+		{"manhattan identical", ManhattanDistance{}, []float64{1, 2, 3}, []float64{1, 2, 3}, 0},
+		{"cosine identical direction", CosineDistance{}, []float64{1, 0}, []float64{2, 0}, 0},
+		{"cosine orthogonal", CosineDistance{}, []float64{1, 0}, []float64{0, 1}, 1},
+		{"cosine opposite", CosineDistance{}, []float64{1, 0}, []float64{-1, 0}, 2},
+		{"cosine zero vector", CosineDistance{}, []float64{0, 0}, []float64{1, 1}, 1},
+		{"cosine both zero vectors", CosineDistance{}, []float64{0, 0}, []float64{0, 0}, 0},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got := c.distance.Compute(c.a, c.b)
+			if math.Abs(got-c.want) > 1e-9 {
+				t.Errorf("Compute(%v, %v) = %v, want %v", c.a, c.b, got, c.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,133 @@
+/* featurestore.go
+
+Alternate output formats for per-galaxy feature/prediction matrices:
+.npy (for downstream Python/Go tooling) and gob-encoded snapshots keyed
+by galaxy ID (so a run can be resumed without re-decoding every JPEG).
+
+@Author: Joyce Noah-Vanhoucke
+*/
+
+package main
+
+import (
+	"bufio"
+	"encoding/gob"
+	"fmt"
+	"os"
+
+	"github.com/kshedden/gonpy"
+)
+
+// WriteMatrixGob gob-encodes the galaxyId -> vector map to path. Used
+// for both feature vectors and predictions, since both are keyed the
+// same way.
+func WriteMatrixGob(matrix map[string][]float64, path string) error {
+	fo, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer fo.Close()
+
+	return gob.NewEncoder(fo).Encode(matrix)
+}
+
+// ReadMatrixGob decodes a galaxyId -> vector map previously written by
+// WriteMatrixGob.
+func ReadMatrixGob(path string) (map[string][]float64, error) {
+	fi, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer fi.Close()
+
+	var matrix map[string][]float64
+	if err := gob.NewDecoder(fi).Decode(&matrix); err != nil {
+		return nil, err
+	}
+	return matrix, nil
+}
+
+// WriteMatrixNpy writes the galaxyId -> vector map to path as a row-major
+// N x D .npy array, and writes the galaxy ID for each row, in order, to
+// the sidecar file path+".ids.txt" since .npy has no way to carry them.
+func WriteMatrixNpy(matrix map[string][]float64, path string) error {
+	galaxyIds, rows := stackFeatures(matrix)
+	if len(rows) == 0 {
+		return fmt.Errorf("WriteMatrixNpy(): matrix is empty")
+	}
+	nCols := len(rows[0])
+
+	flat := make([]float64, 0, len(rows)*nCols)
+	for _, row := range rows {
+		flat = append(flat, row...)
+	}
+
+	wtr, err := gonpy.NewFileWriter(path)
+	if err != nil {
+		return err
+	}
+	wtr.Shape = []int{len(rows), nCols}
+	if err := wtr.WriteFloat64(flat); err != nil {
+		return err
+	}
+
+	return writeGalaxyIds(galaxyIds, path+".ids.txt")
+}
+
+// ReadMatrixNpy reads a galaxyId -> vector map previously written by
+// WriteMatrixNpy, pairing each row back up with its galaxy ID from the
+// path+".ids.txt" sidecar.
+func ReadMatrixNpy(path string) (map[string][]float64, error) {
+	galaxyIds, err := readGalaxyIds(path + ".ids.txt")
+	if err != nil {
+		return nil, err
+	}
+
+	rdr, err := gonpy.NewFileReader(path)
+	if err != nil {
+		return nil, err
+	}
+	flat, err := rdr.GetFloat64()
+	if err != nil {
+		return nil, err
+	}
+	if len(rdr.Shape) != 2 || rdr.Shape[0] != len(galaxyIds) {
+		return nil, fmt.Errorf("ReadMatrixNpy(): shape %v does not match %d galaxy IDs", rdr.Shape, len(galaxyIds))
+	}
+	nCols := rdr.Shape[1]
+
+	matrix := make(map[string][]float64, len(galaxyIds))
+	for i, galaxyId := range galaxyIds {
+		matrix[galaxyId] = flat[i*nCols : (i+1)*nCols]
+	}
+	return matrix, nil
+}
+
+func writeGalaxyIds(galaxyIds []string, path string) error {
+	fo, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer fo.Close()
+
+	w := bufio.NewWriter(fo)
+	for _, galaxyId := range galaxyIds {
+		fmt.Fprintln(w, galaxyId)
+	}
+	return w.Flush()
+}
+
+func readGalaxyIds(path string) ([]string, error) {
+	fi, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer fi.Close()
+
+	var galaxyIds []string
+	scanner := bufio.NewScanner(fi)
+	for scanner.Scan() {
+		galaxyIds = append(galaxyIds, scanner.Text())
+	}
+	return galaxyIds, scanner.Err()
+}
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestWriteReadMatrixGobRoundTrip(t *testing.T) {
+	want := map[string][]float64{
+		"g1": {1, 2, 3},
+		"g2": {4, 5, 6},
+	}
+	path := filepath.Join(t.TempDir(), "features.gob")
+
+	if err := WriteMatrixGob(want, path); err != nil {
+		t.Fatalf("WriteMatrixGob() error = %v", err)
+	}
+
+	got, err := ReadMatrixGob(path)
+	if err != nil {
+		t.Fatalf("ReadMatrixGob() error = %v", err)
+	}
+
+	if len(got) != len(want) {
+		t.Fatalf("ReadMatrixGob() = %v, want %v", got, want)
+	}
+	for galaxyId, wantRow := range want {
+		gotRow, ok := got[galaxyId]
+		if !ok {
+			t.Fatalf("ReadMatrixGob() missing galaxy %q", galaxyId)
+		}
+		if len(gotRow) != len(wantRow) {
+			t.Fatalf("ReadMatrixGob()[%q] = %v, want %v", galaxyId, gotRow, wantRow)
+		}
+		for i := range wantRow {
+			if gotRow[i] != wantRow[i] {
+				t.Fatalf("ReadMatrixGob()[%q] = %v, want %v", galaxyId, gotRow, wantRow)
+			}
+		}
+	}
+}
+
+func TestReadMatrixGobMissingFile(t *testing.T) {
+	if _, err := ReadMatrixGob(filepath.Join(t.TempDir(), "does-not-exist.gob")); err == nil {
+		t.Error("ReadMatrixGob() on a missing file returned a nil error")
+	}
+}
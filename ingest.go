@@ -0,0 +1,133 @@
+/* ingest.go
+
+Concurrent image ingestion pipeline: one goroutine walks the image glob
+and feeds file paths to a pool of worker goroutines, which decode each
+JPEG and extract features in parallel. A collector goroutine assembles
+the results into the final galaxyId -> feature vector map.
+
+@Author: Joyce Noah-Vanhoucke
+*/
+
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"image/jpeg"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/cheggaaa/pb/v3"
+
+	"github.com/noahvanhoucke/GalaxyZooChallenge/imageops"
+)
+
+type featureResult struct {
+	galaxyId string
+	features []float64
+	err      error
+}
+
+// GetGalaxyFeatures walks filepathname, decodes each JPEG and extracts
+// its feature vector with featurizer, spreading the decode work across
+// workers goroutines. A progress bar tracks completion, and per-file
+// decode errors are logged to stderr and skipped rather than aborting
+// the whole run -- unless strict is set, in which case any decode
+// failure aborts the run once the count is known.
+func GetGalaxyFeatures(filepathname string, featurizer Featurizer, preprocess imageops.Config, cache *FeatureCache, workers int, strict bool) map[string][]float64 {
+	listFiles, err := filepath.Glob(filepathname)
+	if err != nil {
+		println("Error using Glob")
+		os.Exit(-1)
+	}
+
+	paths := make(chan string, len(listFiles))
+	results := make(chan featureResult, len(listFiles))
+	bar := pb.StartNew(len(listFiles))
+
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for path := range paths {
+				results <- decodeGalaxyFile(path, featurizer, preprocess, cache)
+				bar.Increment()
+			}
+		}()
+	}
+
+	go func() {
+		for _, path := range listFiles {
+			paths <- path
+		}
+		close(paths)
+	}()
+
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	galaxyFeatures := make(map[string][]float64, len(listFiles))
+	for r := range results {
+		if r.err != nil {
+			fmt.Fprintf(os.Stderr, "GetGalaxyFeatures(): error decoding galaxy %v: %v\n", r.galaxyId, r.err)
+			continue
+		}
+		galaxyFeatures[r.galaxyId] = r.features
+	}
+	bar.Finish()
+
+	if len(galaxyFeatures) != len(listFiles) {
+		missing := len(listFiles) - len(galaxyFeatures)
+		fmt.Fprintf(os.Stderr, "GetGalaxyFeatures(): %d/%d galaxies failed to decode and were skipped\n", missing, len(listFiles))
+		if strict {
+			os.Exit(-1)
+		}
+	}
+
+	return galaxyFeatures
+}
+
+// decodeGalaxyFile decodes the JPEG at path and extracts its feature
+// vector with featurizer, reporting any error alongside the galaxyId it
+// was attempting to process. If cache is non-nil, it is consulted
+// before decoding and populated afterwards, keyed on path + mtime +
+// featurizer/preprocess configuration.
+func decodeGalaxyFile(path string, featurizer Featurizer, preprocess imageops.Config, cache *FeatureCache) featureResult {
+	galaxyId := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return featureResult{galaxyId: galaxyId, err: err}
+	}
+	featurizerVersion := fmt.Sprintf("%s|%v", featurizer.Name(), preprocess)
+
+	if cache != nil {
+		if features, ok := cache.Get(path, info.ModTime(), featurizerVersion); ok {
+			return featureResult{galaxyId: galaxyId, features: features}
+		}
+	}
+
+	file, err := os.OpenFile(path, os.O_RDONLY, 0666)
+	if err != nil {
+		return featureResult{galaxyId: galaxyId, err: err}
+	}
+	defer file.Close()
+
+	img, err := jpeg.Decode(bufio.NewReader(file))
+	if err != nil {
+		return featureResult{galaxyId: galaxyId, err: err}
+	}
+
+	img = imageops.Apply(img, preprocess)
+	features := featurizer.Features(img)
+
+	if cache != nil {
+		cache.Put(path, info.ModTime(), featurizerVersion, features)
+	}
+	return featureResult{galaxyId: galaxyId, features: features}
+}
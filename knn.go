@@ -0,0 +1,101 @@
+/* knn.go
+
+k-nearest-neighbors classifier over galaxy feature vectors. For each test
+galaxy, finds the K closest training galaxies in feature space and
+predicts the (optionally distance-weighted) mean of their 37 class
+probabilities.
+
+@Author: Joyce Noah-Vanhoucke
+*/
+
+package main
+
+import (
+	"os"
+	"sort"
+)
+
+// KNNClassifier predicts Class probabilities for a galaxy from the K
+// training galaxies nearest to it in feature space.
+type KNNClassifier struct {
+	Distance Distance
+	K        int
+	Weighted bool
+
+	trainFeatures  map[string][]float64
+	trainSolutions map[string][]float64
+}
+
+// NewKNNClassifier builds a classifier that finds the k nearest training
+// galaxies under distance, and averages their solutions -- weighted by
+// inverse distance when weighted is true, unweighted otherwise.
+func NewKNNClassifier(distance Distance, k int, weighted bool) *KNNClassifier {
+	return &KNNClassifier{Distance: distance, K: k, Weighted: weighted}
+}
+
+// Fit records the training feature vectors and their known solutions so
+// Predict can search over them.
+func (c *KNNClassifier) Fit(trainFeatures map[string][]float64, trainSolutions map[string][]float64) {
+	c.trainFeatures = trainFeatures
+	c.trainSolutions = trainSolutions
+}
+
+type neighbor struct {
+	galaxyId string
+	distance float64
+}
+
+// Predict returns the 37 Class probabilities for the galaxy described by
+// features, averaged over the K nearest training galaxies.
+func (c *KNNClassifier) Predict(features []float64) []float64 {
+	neighbors := make([]neighbor, 0, len(c.trainFeatures))
+	for galaxyId, trainFeatures := range c.trainFeatures {
+		neighbors = append(neighbors, neighbor{
+			galaxyId: galaxyId,
+			distance: c.Distance.Compute(features, trainFeatures),
+		})
+	}
+	sort.Slice(neighbors, func(i, j int) bool {
+		return neighbors[i].distance < neighbors[j].distance
+	})
+
+	k := c.K
+	if k > len(neighbors) {
+		k = len(neighbors)
+	}
+	if k == 0 {
+		println("KNNClassifier.Predict(): no training galaxies to predict from")
+		os.Exit(-1)
+	}
+	neighbors = neighbors[:k]
+
+	NProbabilities := 37
+	prediction := make([]float64, NProbabilities)
+	totalWeight := 0.0
+	for _, n := range neighbors {
+		weight := 1.0
+		if c.Weighted {
+			// Guard against a zero distance (an identical training galaxy)
+			// dominating the average by division.
+			weight = 1.0 / (n.distance + 1e-9)
+		}
+		solution := c.trainSolutions[n.galaxyId]
+		for i := 0; i < NProbabilities; i++ {
+			prediction[i] += weight * solution[i]
+		}
+		totalWeight += weight
+	}
+	for i := range prediction {
+		prediction[i] /= totalWeight
+	}
+	return prediction
+}
+
+// PredictAll runs Predict for every galaxy in testFeatures.
+func (c *KNNClassifier) PredictAll(testFeatures map[string][]float64) map[string][]float64 {
+	predictions := make(map[string][]float64)
+	for galaxyId, features := range testFeatures {
+		predictions[galaxyId] = c.Predict(features)
+	}
+	return predictions
+}
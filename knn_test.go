@@ -0,0 +1,63 @@
+package main
+
+import "testing"
+
+func solutions37(first float64) []float64 {
+	solution := make([]float64, 37)
+	solution[0] = first
+	return solution
+}
+
+func TestKNNClassifierPredictUnweighted(t *testing.T) {
+	classifier := NewKNNClassifier(EuclideanDistance{}, 2, false)
+	classifier.Fit(
+		map[string][]float64{
+			"near1": {0, 0},
+			"near2": {1, 0},
+			"far":   {100, 100},
+		},
+		map[string][]float64{
+			"near1": solutions37(1.0),
+			"near2": solutions37(3.0),
+			"far":   solutions37(99.0),
+		},
+	)
+
+	got := classifier.Predict([]float64{0, 0})
+	want := 2.0 // unweighted mean of the two nearest neighbors' first class value
+	if got[0] != want {
+		t.Errorf("Predict()[0] = %v, want %v", got[0], want)
+	}
+}
+
+func TestKNNClassifierPredictWeightedFavorsCloserNeighbor(t *testing.T) {
+	classifier := NewKNNClassifier(EuclideanDistance{}, 2, true)
+	classifier.Fit(
+		map[string][]float64{
+			"close": {0, 0},
+			"far":   {10, 0},
+		},
+		map[string][]float64{
+			"close": solutions37(1.0),
+			"far":   solutions37(101.0),
+		},
+	)
+
+	got := classifier.Predict([]float64{1, 0})
+	if got[0] <= 1.0 || got[0] >= 51.0 {
+		t.Errorf("Predict()[0] = %v, want a value pulled toward the closer neighbor's 1.0", got[0])
+	}
+}
+
+func TestKNNClassifierPredictClampsKToAvailableNeighbors(t *testing.T) {
+	classifier := NewKNNClassifier(EuclideanDistance{}, 10, false)
+	classifier.Fit(
+		map[string][]float64{"only": {0, 0}},
+		map[string][]float64{"only": solutions37(7.0)},
+	)
+
+	got := classifier.Predict([]float64{5, 5})
+	if got[0] != 7.0 {
+		t.Errorf("Predict()[0] = %v, want 7.0 from the single available neighbor", got[0])
+	}
+}
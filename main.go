@@ -0,0 +1,293 @@
+/* main.go
+
+k-NN benchmark for Kaggle's Galaxy Zoo competition.
+For each test galaxy, featurizes the image, finds the k nearest training
+galaxies in feature space and predicts the distance-weighted average of
+their Class probabilities. Defaults to the same central-pixel patch
+feature the original central-pixel benchmark clustered on, now searched
+with true k-NN instead of a hash bucket.
+
+@Author: Joyce Noah-Vanhoucke
+@Created: 20 December 2013
+*/
+
+package main
+
+import (
+	"encoding/csv"
+	"flag"
+	"io"
+	"os"
+	"runtime"
+	"strconv"
+
+	"github.com/noahvanhoucke/GalaxyZooChallenge/imageops"
+)
+
+func AssignClassValues(splitrow []string) []float64 {
+	/* Converts the Class probability values read from file from
+	   string to float. */
+
+	floatrow := make([]float64, 37)
+	for i := 0; i < len(splitrow); i++ {
+		val, err := strconv.ParseFloat(splitrow[i], 64)
+		floatrow[i] = val
+		if err != nil {
+			println("Error converting string to float ", err)
+			os.Exit(-1)
+		}
+	}
+	return floatrow
+}
+
+func GetTrainingSolutions(filename string) (map[string][]float64, []string) {
+	/* Reads the training solutions file. Returns dictionary with GalaxyId
+	   as the key and values as an array of float presenting the Class probability values. */
+
+	trainingSolutions := make(map[string][]float64)
+	trainFile, err := os.OpenFile(filename, os.O_RDONLY, 0666)
+	if err != nil {
+		println("Error opening file: ", err)
+		os.Exit(-1)
+	}
+	defer trainFile.Close()
+
+	reader := csv.NewReader(trainFile)
+	headerRow, err := reader.Read()
+	if err != nil {
+		println("Error reading training solutions header: ", err.Error())
+		os.Exit(-1)
+	}
+	for {
+		row, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			println("Error reading training solutions: ", err.Error())
+			os.Exit(-1)
+		}
+		values := AssignClassValues(row[1:])
+		trainingSolutions[row[0]] = values
+	}
+	return trainingSolutions, headerRow
+}
+
+func BuildPredictionRow(galaxyId string, pred []float64) []string {
+	/* Build the string to be written to file using the csv library. */
+
+	var line []string
+	line = append(line, galaxyId)
+	for i := 0; i < len(pred); i++ {
+		line = append(line, strconv.FormatFloat(pred[i], 'f', -1, 64))
+	}
+
+	if len(line) != 38 {
+		println("BuildPredictionRow(): line does not have 38 elements, it has: ", len(line))
+		os.Exit(-1)
+	}
+	return line
+}
+
+func WritePredictions(predictions map[string][]float64, testPredictionFile string, headerRow []string) {
+	/* Writes the k-NN predictions for each test galaxy to testPredictionFile. */
+
+	fo, err := os.Create(testPredictionFile)
+	if err != nil {
+		println("WritePredictions(): Error opening file: ", err)
+		os.Exit(-1)
+	}
+	defer fo.Close()
+	wr := csv.NewWriter(fo)
+
+	err = wr.Write(headerRow)
+	count := 0
+	for galaxyId, pred := range predictions {
+		if len(pred) != 37 {
+			println("Do not have 37 predictions for galaxy ", galaxyId)
+			os.Exit(-1)
+		}
+		err = wr.Write(BuildPredictionRow(galaxyId, pred))
+		count += 1
+	}
+	wr.Flush()
+	println("Number of predictions made = ", count)
+}
+
+// main dispatches to the `pca` subcommand, or to the default predict
+// flow when no recognized subcommand is given.
+func main() {
+	if len(os.Args) > 1 && os.Args[1] == "pca" {
+		runPCA(os.Args[2:])
+		return
+	}
+	runPredict(os.Args[1:])
+}
+
+// runPredict implements the default subcommand: featurize training and
+// test images, optionally project them through a fitted PCA model, then
+// predict test Class probabilities via k-NN over the (projected)
+// feature space.
+func runPredict(args []string) {
+	fs := flag.NewFlagSet("predict", flag.ExitOnError)
+	trainingImages := fs.String("training-images", "images_training/*.jpg", "glob of training images")
+	testImages := fs.String("test-images", "images_test/*.jpg", "glob of test images")
+	solutionsFile := fs.String("solutions-file", "solutions_training.csv", "path to the training solutions CSV")
+	workers := fs.Int("workers", runtime.NumCPU(), "number of concurrent image-decode workers")
+	strict := fs.Bool("strict", false, "abort the run if any image fails to decode, instead of skipping it and continuing")
+	cropSize := fs.Int("crop-size", 0, "center-crop images to this size in pixels before featurizing (0 disables)")
+	resizeWidth := fs.Int("resize-width", 0, "resize images to this width in pixels before featurizing (0 disables)")
+	resizeHeight := fs.Int("resize-height", 0, "resize images to this height in pixels before featurizing (0 disables)")
+	rotateDegrees := fs.Float64("rotate-degrees", 0, "rotate images by this many degrees before featurizing (0 disables)")
+	flipH := fs.Bool("flip-h", false, "flip images horizontally before featurizing")
+	flipV := fs.Bool("flip-v", false, "flip images vertically before featurizing")
+	brightness := fs.Float64("brightness", 0, "brightness adjustment in [-100, 100] applied before featurizing (0 disables)")
+	contrast := fs.Float64("contrast", 0, "contrast adjustment in [-100, 100] applied before featurizing (0 disables)")
+	preprocessConfigFile := fs.String("preprocess-config", "", "path to a JSON imageops.Config; overrides the preprocessing flags above")
+	pcaModelFile := fs.String("pca-model", "", "path to a PCA model fitted by the pca subcommand; projects features into its reduced space before k-NN")
+	featureCacheDir := fs.String("feature-cache-dir", "", "directory of gob-encoded feature snapshots; reused across runs instead of re-decoding JPEGs when present")
+	cacheDir := fs.String("cache-dir", "", "directory for the per-image LRU feature cache, persisted across runs as small binary blobs (\"\" disables)")
+	cacheSize := fs.Int("cache-size", defaultFeatureCacheSize, "number of feature vectors to keep in the in-memory LRU cache")
+	featuresNpyDir := fs.String("features-npy-dir", "", "directory to additionally write training/test features as .npy files (\"\" disables)")
+	predictionsNpyFile := fs.String("predictions-npy", "", "path to additionally write test predictions as a .npy file (\"\" disables)")
+	featurizerName := fs.String("featurizer", "central-pixel", "feature extractor to use: central-pixel, patch-stats, thumbnail, hsv-histogram")
+	patchSize := fs.Int("patch-size", 10, "patch size in pixels used by the central-pixel and patch-stats featurizers")
+	thumbWidth := fs.Int("thumb-width", 8, "thumbnail width used by the thumbnail featurizer")
+	thumbHeight := fs.Int("thumb-height", 8, "thumbnail height used by the thumbnail featurizer")
+	hsvBins := fs.Int("hsv-bins", 36, "number of hue bins used by the hsv-histogram featurizer")
+	distanceName := fs.String("distance", "euclidean", "distance metric to use: euclidean, manhattan, cosine")
+	k := fs.Int("k", 10, "number of nearest training galaxies to average over")
+	weighted := fs.Bool("weighted", true, "weight neighbor predictions by inverse distance instead of averaging them unweighted")
+	fs.Parse(args)
+
+	preprocess := imageops.Config{
+		CropSize:      *cropSize,
+		ResizeWidth:   *resizeWidth,
+		ResizeHeight:  *resizeHeight,
+		RotateDegrees: *rotateDegrees,
+		FlipH:         *flipH,
+		FlipV:         *flipV,
+		Brightness:    *brightness,
+		Contrast:      *contrast,
+	}
+	if *preprocessConfigFile != "" {
+		cfg, err := imageops.LoadConfig(*preprocessConfigFile)
+		if err != nil {
+			println("runPredict(): Error loading preprocess config: ", err.Error())
+			os.Exit(-1)
+		}
+		preprocess = cfg
+	}
+
+	cache, err := NewFeatureCache(*cacheSize, *cacheDir)
+	if err != nil {
+		println("runPredict(): Error creating feature cache: ", err.Error())
+		os.Exit(-1)
+	}
+
+	// Featurize training and test images. Defaults to the central-pixel
+	// patch the original benchmark clustered on; --featurizer selects a
+	// richer feature space.
+	featurizer := buildFeaturizer(*featurizerName, *patchSize, *thumbWidth, *thumbHeight, *hsvBins)
+
+	trainingFeatures := getOrComputeFeatures(*featureCacheDir, "training", *trainingImages, featurizer, preprocess, cache, *workers, *strict)
+	testFeatures := getOrComputeFeatures(*featureCacheDir, "test", *testImages, featurizer, preprocess, cache, *workers, *strict)
+
+	if *featuresNpyDir != "" {
+		if err := WriteMatrixNpy(trainingFeatures, *featuresNpyDir+"/training_features.npy"); err != nil {
+			println("runPredict(): Error writing training features npy: ", err.Error())
+			os.Exit(-1)
+		}
+		if err := WriteMatrixNpy(testFeatures, *featuresNpyDir+"/test_features.npy"); err != nil {
+			println("runPredict(): Error writing test features npy: ", err.Error())
+			os.Exit(-1)
+		}
+	}
+
+	if *pcaModelFile != "" {
+		pcaModel, err := LoadPCAModel(*pcaModelFile)
+		if err != nil {
+			println("runPredict(): Error loading PCA model: ", err.Error())
+			os.Exit(-1)
+		}
+		trainingFeatures = pcaModel.ProjectAll(trainingFeatures)
+		testFeatures = pcaModel.ProjectAll(testFeatures)
+	}
+
+	trainingSolutions, headerRow := GetTrainingSolutions(*solutionsFile)
+
+	// Find the k nearest training galaxies in feature space and predict
+	// the (optionally distance-weighted) average of their Class
+	// probabilities.
+	classifier := NewKNNClassifier(buildDistance(*distanceName), *k, *weighted)
+	classifier.Fit(trainingFeatures, trainingSolutions)
+	testPredictions := classifier.PredictAll(testFeatures)
+
+	testPredictionFile := "lastrun.csv"
+	WritePredictions(testPredictions, testPredictionFile, headerRow)
+	if *predictionsNpyFile != "" {
+		if err := WriteMatrixNpy(testPredictions, *predictionsNpyFile); err != nil {
+			println("runPredict(): Error writing predictions npy: ", err.Error())
+			os.Exit(-1)
+		}
+	}
+
+	println("\nEnd Program. Success!")
+}
+
+// getOrComputeFeatures loads a gob feature snapshot for name (e.g.
+// "training" or "test") from snapshotDir if present, otherwise
+// featurizes imagesPath from scratch (consulting cache per-image) and,
+// when snapshotDir is set, writes the whole-run snapshot for the next
+// run to reuse.
+func getOrComputeFeatures(snapshotDir, name, imagesPath string, featurizer Featurizer, preprocess imageops.Config, cache *FeatureCache, workers int, strict bool) map[string][]float64 {
+	if snapshotDir == "" {
+		return GetGalaxyFeatures(imagesPath, featurizer, preprocess, cache, workers, strict)
+	}
+
+	snapshotPath := snapshotDir + "/" + name + "_features.gob"
+	if cached, err := ReadMatrixGob(snapshotPath); err == nil {
+		return cached
+	}
+
+	features := GetGalaxyFeatures(imagesPath, featurizer, preprocess, cache, workers, strict)
+	if err := WriteMatrixGob(features, snapshotPath); err != nil {
+		println("getOrComputeFeatures(): Error writing feature snapshot: ", err.Error())
+		os.Exit(-1)
+	}
+	return features
+}
+
+// buildFeaturizer selects a Featurizer by name for the --featurizer flag.
+func buildFeaturizer(name string, patchSize, thumbWidth, thumbHeight, hsvBins int) Featurizer {
+	switch name {
+	case "central-pixel":
+		return CentralPixelFeaturizer{PatchSize: patchSize}
+	case "patch-stats":
+		return PatchStatsFeaturizer{PatchSize: patchSize}
+	case "thumbnail":
+		return ThumbnailFeaturizer{Width: thumbWidth, Height: thumbHeight}
+	case "hsv-histogram":
+		return HSVHistogramFeaturizer{Bins: hsvBins}
+	default:
+		println("buildFeaturizer(): unknown featurizer: ", name)
+		os.Exit(-1)
+		return nil
+	}
+}
+
+// buildDistance selects a Distance by name for the --distance flag.
+func buildDistance(name string) Distance {
+	switch name {
+	case "euclidean":
+		return EuclideanDistance{}
+	case "manhattan":
+		return ManhattanDistance{}
+	case "cosine":
+		return CosineDistance{}
+	default:
+		println("buildDistance(): unknown distance metric: ", name)
+		os.Exit(-1)
+		return nil
+	}
+}
@@ -0,0 +1,243 @@
+/* pca.go
+
+`pca` subcommand: fits a PCA projection over a set of per-galaxy feature
+vectors and persists it to disk, so training and test galaxies can later
+be projected into a reduced feature space before clustering or k-NN.
+
+@Author: Joyce Noah-Vanhoucke
+*/
+
+package main
+
+import (
+	"encoding/gob"
+	"flag"
+	"os"
+	"runtime"
+	"sort"
+
+	"gonum.org/v1/gonum/mat"
+	"gonum.org/v1/gonum/stat"
+
+	"github.com/noahvanhoucke/GalaxyZooChallenge/imageops"
+)
+
+// PCAModel projects a feature vector into the reduced space learned by
+// FitPCA: the feature dimensions that survived the min-coverage filter,
+// mean-centered, then multiplied by the retained principal components.
+type PCAModel struct {
+	KeepIdx    []int
+	Mean       []float64
+	Components [][]float64 // len(KeepIdx) x NComponents
+}
+
+// pcaModelGob is the on-disk representation of a PCAModel. PCAModel's
+// fields are already gob-friendly, but a named type keeps the on-disk
+// format independent of the in-memory one if PCAModel grows unexported
+// fields later.
+type pcaModelGob struct {
+	KeepIdx    []int
+	Mean       []float64
+	Components [][]float64
+}
+
+// stackFeatures orders galaxyIds deterministically and stacks their
+// feature vectors into a dense rows x cols matrix.
+func stackFeatures(features map[string][]float64) (galaxyIds []string, matrix [][]float64) {
+	galaxyIds = make([]string, 0, len(features))
+	for galaxyId := range features {
+		galaxyIds = append(galaxyIds, galaxyId)
+	}
+	sort.Strings(galaxyIds)
+
+	matrix = make([][]float64, len(galaxyIds))
+	for i, galaxyId := range galaxyIds {
+		matrix[i] = features[galaxyId]
+	}
+	return galaxyIds, matrix
+}
+
+// coverageFilter returns the column indices whose fraction of non-zero
+// entries across matrix's rows is at least minCoverage.
+func coverageFilter(matrix [][]float64, minCoverage float64) []int {
+	if len(matrix) == 0 {
+		return nil
+	}
+	nCols := len(matrix[0])
+	counts := make([]int, nCols)
+	for _, row := range matrix {
+		for j, v := range row {
+			if v != 0 {
+				counts[j]++
+			}
+		}
+	}
+
+	var keepIdx []int
+	for j, count := range counts {
+		if float64(count)/float64(len(matrix)) >= minCoverage {
+			keepIdx = append(keepIdx, j)
+		}
+	}
+	return keepIdx
+}
+
+func selectColumns(row []float64, keepIdx []int) []float64 {
+	selected := make([]float64, len(keepIdx))
+	for i, j := range keepIdx {
+		selected[i] = row[j]
+	}
+	return selected
+}
+
+// FitPCA stacks trainFeatures into a matrix, drops feature dimensions
+// present in fewer than minCoverage of galaxies, and fits a projection
+// onto the top nComponents principal components.
+func FitPCA(trainFeatures map[string][]float64, nComponents int, minCoverage float64) *PCAModel {
+	_, matrix := stackFeatures(trainFeatures)
+	keepIdx := coverageFilter(matrix, minCoverage)
+	if len(keepIdx) == 0 {
+		println("FitPCA(): min-coverage dropped every feature dimension, nothing left to fit")
+		os.Exit(-1)
+	}
+
+	filtered := make([][]float64, len(matrix))
+	for i, row := range matrix {
+		filtered[i] = selectColumns(row, keepIdx)
+	}
+
+	mean := make([]float64, len(keepIdx))
+	for _, row := range filtered {
+		for j, v := range row {
+			mean[j] += v
+		}
+	}
+	for j := range mean {
+		mean[j] /= float64(len(filtered))
+	}
+
+	centered := mat.NewDense(len(filtered), len(keepIdx), nil)
+	for i, row := range filtered {
+		for j, v := range row {
+			centered.Set(i, j, v-mean[j])
+		}
+	}
+
+	var pc stat.PC
+	if ok := pc.PrincipalComponents(centered, nil); !ok {
+		println("FitPCA(): PrincipalComponents failed to converge")
+		os.Exit(-1)
+	}
+
+	if nComponents > len(keepIdx) {
+		nComponents = len(keepIdx)
+	}
+	var vectors mat.Dense
+	pc.VectorsTo(&vectors)
+
+	components := make([][]float64, len(keepIdx))
+	for i := range components {
+		components[i] = make([]float64, nComponents)
+		for k := 0; k < nComponents; k++ {
+			components[i][k] = vectors.At(i, k)
+		}
+	}
+
+	return &PCAModel{KeepIdx: keepIdx, Mean: mean, Components: components}
+}
+
+// Project maps a full feature vector into the reduced PCA space.
+func (m *PCAModel) Project(features []float64) []float64 {
+	if len(m.Components) == 0 {
+		println("PCAModel.Project(): model has no components, was it fitted successfully?")
+		os.Exit(-1)
+	}
+
+	selected := selectColumns(features, m.KeepIdx)
+	for j := range selected {
+		selected[j] -= m.Mean[j]
+	}
+
+	nComponents := len(m.Components[0])
+	projected := make([]float64, nComponents)
+	for k := 0; k < nComponents; k++ {
+		for j, v := range selected {
+			projected[k] += v * m.Components[j][k]
+		}
+	}
+	return projected
+}
+
+// ProjectAll projects every galaxy's feature vector in features.
+func (m *PCAModel) ProjectAll(features map[string][]float64) map[string][]float64 {
+	projected := make(map[string][]float64, len(features))
+	for galaxyId, f := range features {
+		projected[galaxyId] = m.Project(f)
+	}
+	return projected
+}
+
+// SavePCAModel gob-encodes model to path.
+func SavePCAModel(model *PCAModel, path string) error {
+	fo, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer fo.Close()
+
+	return gob.NewEncoder(fo).Encode(pcaModelGob{
+		KeepIdx:    model.KeepIdx,
+		Mean:       model.Mean,
+		Components: model.Components,
+	})
+}
+
+// LoadPCAModel reads a PCAModel previously written by SavePCAModel.
+func LoadPCAModel(path string) (*PCAModel, error) {
+	fi, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer fi.Close()
+
+	var g pcaModelGob
+	if err := gob.NewDecoder(fi).Decode(&g); err != nil {
+		return nil, err
+	}
+	return &PCAModel{KeepIdx: g.KeepIdx, Mean: g.Mean, Components: g.Components}, nil
+}
+
+// runPCA implements the `pca` subcommand: featurize the training images
+// with a downsampled thumbnail featurizer, fit a PCA projection over the
+// resulting vectors, and persist it for later projection of both
+// training and test galaxies.
+func runPCA(args []string) {
+	fs := flag.NewFlagSet("pca", flag.ExitOnError)
+	workers := fs.Int("workers", runtime.NumCPU(), "number of concurrent image-decode workers")
+	strict := fs.Bool("strict", false, "abort the run if any image fails to decode, instead of skipping it and continuing")
+	nComponents := fs.Int("pca-components", 20, "number of principal components to retain")
+	minCoverage := fs.Float64("min-coverage", 0.0, "drop feature dimensions present in fewer than this fraction of galaxies")
+	thumbWidth := fs.Int("thumb-width", 8, "thumbnail width used to build the feature matrix")
+	thumbHeight := fs.Int("thumb-height", 8, "thumbnail height used to build the feature matrix")
+	trainingImagesPath := fs.String("training-images", "images_training/*.jpg", "glob of training images to fit the PCA model on")
+	outputFile := fs.String("out", "pca_model.gob", "where to write the fitted PCA model")
+	cacheDir := fs.String("cache-dir", "", "directory to persist the feature cache across runs (\"\" disables)")
+	fs.Parse(args)
+
+	cache, err := NewFeatureCache(defaultFeatureCacheSize, *cacheDir)
+	if err != nil {
+		println("runPCA(): Error creating feature cache: ", err.Error())
+		os.Exit(-1)
+	}
+
+	featurizer := ThumbnailFeaturizer{Width: *thumbWidth, Height: *thumbHeight}
+	trainingFeatures := GetGalaxyFeatures(*trainingImagesPath, featurizer, imageops.Config{}, cache, *workers, *strict)
+
+	model := FitPCA(trainingFeatures, *nComponents, *minCoverage)
+	if err := SavePCAModel(model, *outputFile); err != nil {
+		println("runPCA(): Error saving PCA model: ", err.Error())
+		os.Exit(-1)
+	}
+
+	println("runPCA(): Fitted PCA model with ", len(model.Components[0]), " components, written to ", *outputFile)
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"math"
+	"testing"
+)
+
+func TestCoverageFilterDropsSparseColumns(t *testing.T) {
+	matrix := [][]float64{
+		{1, 0, 1},
+		{1, 0, 1},
+		{1, 1, 1},
+	}
+
+	got := coverageFilter(matrix, 0.5)
+	want := []int{0, 2}
+	if len(got) != len(want) {
+		t.Fatalf("coverageFilter() = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("coverageFilter() = %v, want %v", got, want)
+		}
+	}
+}
+
+func TestCoverageFilterEmptyMatrix(t *testing.T) {
+	if got := coverageFilter(nil, 0.5); got != nil {
+		t.Errorf("coverageFilter(nil, ...) = %v, want nil", got)
+	}
+}
+
+func TestFitPCAAndProjectRoundTrip(t *testing.T) {
+	trainFeatures := map[string][]float64{
+		"g1": {1, 10, 0},
+		"g2": {2, 20, 0},
+		"g3": {3, 30, 0},
+		"g4": {4, 40, 0},
+	}
+
+	model := FitPCA(trainFeatures, 2, 0.5)
+
+	wantKeepIdx := []int{0, 1}
+	if len(model.KeepIdx) != len(wantKeepIdx) {
+		t.Fatalf("KeepIdx = %v, want %v", model.KeepIdx, wantKeepIdx)
+	}
+	for i := range wantKeepIdx {
+		if model.KeepIdx[i] != wantKeepIdx[i] {
+			t.Fatalf("KeepIdx = %v, want %v", model.KeepIdx, wantKeepIdx)
+		}
+	}
+
+	wantMean := []float64{2.5, 25}
+	for j, v := range wantMean {
+		if math.Abs(model.Mean[j]-v) > 1e-9 {
+			t.Errorf("Mean[%d] = %v, want %v", j, model.Mean[j], v)
+		}
+	}
+
+	// The two retained columns are perfectly correlated (col1 = 10*col0),
+	// so a single principal component should explain the feature space and
+	// projecting a training point should reproduce its signed distance
+	// along that component consistently with its neighbors.
+	projected := model.ProjectAll(trainFeatures)
+	if len(projected["g1"]) != 2 {
+		t.Fatalf("Project() returned %d components, want 2", len(projected["g1"]))
+	}
+
+	// g1 and g4 are symmetric about the mean, so their projections onto
+	// the first component should be equal in magnitude and opposite in sign.
+	g1, g4 := projected["g1"][0], projected["g4"][0]
+	if math.Abs(g1+g4) > 1e-6 {
+		t.Errorf("projections of symmetric points should cancel, got g1=%v g4=%v", g1, g4)
+	}
+}